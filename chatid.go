@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ChatID is a Recipient that accepts either a numeric Telegram chat ID or
+// an "@username" for channels and public supergroups, mirroring the union
+// the Bot API itself accepts in chat_id parameters. ID and Username are
+// mutually exclusive; when both are set, ID takes precedence.
+type ChatID struct {
+	ID       int64
+	Username string
+}
+
+// Recipient returns the numeric ID, or the @username when ID is unset, so
+// ChatID satisfies the Recipient interface.
+func (c ChatID) Recipient() string {
+	if c.ID != 0 {
+		return strconv.FormatInt(c.ID, 10)
+	}
+	return c.Username
+}
+
+// MarshalJSON encodes ChatID the way outgoing Bot API requests expect: the
+// numeric chat_id when set, otherwise the @username string.
+func (c ChatID) MarshalJSON() ([]byte, error) {
+	if c.ID != 0 {
+		return json.Marshal(c.ID)
+	}
+	return json.Marshal(c.Username)
+}
+
+// ParseChatID parses s as either a numeric chat ID or an "@username",
+// prefixing a missing leading "@" onto the latter.
+func ParseChatID(s string) (ChatID, error) {
+	if s == "" {
+		return ChatID{}, errors.New("telebot: empty chat id")
+	}
+	if id, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return ChatID{ID: id}, nil
+	}
+	if !strings.HasPrefix(s, "@") {
+		s = "@" + s
+	}
+	return ChatID{Username: s}, nil
+}
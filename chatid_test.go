@@ -0,0 +1,44 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseChatID(t *testing.T) {
+	cases := []struct {
+		in   string
+		want ChatID
+	}{
+		{"123456", ChatID{ID: 123456}},
+		{"-1001234567890", ChatID{ID: -1001234567890}},
+		{"@mychannel", ChatID{Username: "@mychannel"}},
+		{"mychannel", ChatID{Username: "@mychannel"}},
+	}
+
+	for _, c := range cases {
+		got, err := ParseChatID(c.in)
+		if err != nil {
+			t.Fatalf("ParseChatID(%q): %v", c.in, err)
+		}
+		if got != c.want {
+			t.Errorf("ParseChatID(%q) = %+v, want %+v", c.in, got, c.want)
+		}
+	}
+
+	if _, err := ParseChatID(""); err == nil {
+		t.Error("ParseChatID(\"\") should error")
+	}
+}
+
+func TestChatIDMarshalJSON(t *testing.T) {
+	b, err := json.Marshal(ChatID{ID: 42})
+	if err != nil || string(b) != "42" {
+		t.Errorf("got %s, %v; want 42, <nil>", b, err)
+	}
+
+	b, err = json.Marshal(ChatID{Username: "@mychannel"})
+	if err != nil || string(b) != `"@mychannel"` {
+		t.Errorf("got %s, %v; want \"@mychannel\", <nil>", b, err)
+	}
+}
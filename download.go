@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// ErrFileTooLarge is returned by DownloadFile/DownloadFileToPath when a
+// file exceeds the limit set with WithMaxBytes.
+var ErrFileTooLarge = errors.New("telebot: file exceeds configured max size")
+
+// downloadOptions collects settings accepted as DownloadOption values.
+type downloadOptions struct {
+	ctx      context.Context
+	maxBytes int64
+}
+
+// DownloadOption configures a Bot.DownloadFile or Bot.DownloadFileToPath
+// call.
+type DownloadOption func(*downloadOptions)
+
+// WithContext makes the download cancellable/timeoutable independently of
+// the Bot's global HTTP client timeout.
+func WithContext(ctx context.Context) DownloadOption {
+	return func(o *downloadOptions) { o.ctx = ctx }
+}
+
+// WithMaxBytes rejects files larger than n bytes, checked against
+// File.FileSize (filled in from the response's Content-Length when the Bot
+// API left it unset, or on a resumed 206 response) before any of the body
+// is buffered, and enforced again while the body is copied.
+func WithMaxBytes(n int64) DownloadOption {
+	return func(o *downloadOptions) { o.maxBytes = n }
+}
+
+func newDownloadOptions(opts []DownloadOption) *downloadOptions {
+	o := &downloadOptions{ctx: context.Background()}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// openDownload resolves file via FileByID and opens its content, sending a
+// Range request when resumeFrom is positive. It reports whether the
+// server honored the range with a 206 response.
+func (b *Bot) openDownload(o *downloadOptions, file *File, resumeFrom int64) (resp *http.Response, partial bool, err error) {
+	f, err := b.FileByID(file.FileID)
+	if err != nil {
+		return nil, false, err
+	}
+	file.FilePath = f.FilePath
+
+	url := b.URL + "/file/bot" + b.Token + "/" + f.FilePath
+	req, err := http.NewRequestWithContext(o.ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, false, wrapError(err)
+	}
+	if resumeFrom > 0 {
+		req.Header.Set("Range", "bytes="+strconv.FormatInt(resumeFrom, 10)+"-")
+	}
+
+	resp, err = b.client.Do(req)
+	if err != nil {
+		return nil, false, wrapError(err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		if file.FileSize == 0 {
+			if size, err := strconv.Atoi(resp.Header.Get("Content-Length")); err == nil {
+				file.FileSize = size
+			}
+		}
+		return resp, false, nil
+	case http.StatusPartialContent:
+		if size, err := strconv.Atoi(resp.Header.Get("Content-Length")); err == nil {
+			file.FileSize = int(resumeFrom) + size
+		}
+		return resp, true, nil
+	case http.StatusRequestedRangeNotSatisfiable:
+		// The range we asked to resume from is at or past the end of the
+		// file, i.e. resumeFrom already holds the complete file. Report
+		// this as success with a nil response rather than an error.
+		resp.Body.Close()
+		return nil, false, nil
+	default:
+		defer resp.Body.Close()
+		var r apiResponse
+		if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+			return nil, false, errors.Errorf("telebot: expected status 200 but got %s", resp.Status)
+		}
+		return nil, false, r.asError()
+	}
+}
+
+// DownloadFile streams file's content into dst, honoring WithMaxBytes and
+// WithContext. It returns the number of bytes written.
+func (b *Bot) DownloadFile(file *File, dst io.Writer, opts ...DownloadOption) (int64, error) {
+	o := newDownloadOptions(opts)
+
+	if o.maxBytes > 0 && int64(file.FileSize) > o.maxBytes {
+		return 0, ErrFileTooLarge
+	}
+
+	resp, _, err := b.openDownload(o, file, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if o.maxBytes > 0 && int64(file.FileSize) > o.maxBytes {
+		return 0, ErrFileTooLarge
+	}
+
+	body := io.Reader(resp.Body)
+	if o.maxBytes > 0 {
+		body = io.LimitReader(resp.Body, o.maxBytes+1)
+	}
+
+	n, err := io.Copy(dst, body)
+	if err != nil {
+		return n, wrapError(err)
+	}
+	if o.maxBytes > 0 && n > o.maxBytes {
+		return n, ErrFileTooLarge
+	}
+	return n, nil
+}
+
+// DownloadFileToPath downloads file to the local path, resuming from any
+// bytes already present there when the server honors an HTTP Range
+// request.
+func (b *Bot) DownloadFileToPath(file *File, path string, opts ...DownloadOption) error {
+	o := newDownloadOptions(opts)
+
+	var resumeFrom int64
+	if stat, err := os.Stat(path); err == nil {
+		resumeFrom = stat.Size()
+	}
+	if file.FileSize > 0 && resumeFrom >= int64(file.FileSize) {
+		// The local file already matches the server's reported size;
+		// skip the Range request entirely rather than asking the file
+		// server to resume from (or past) the end of the file.
+		return nil
+	}
+
+	resp, partial, err := b.openDownload(o, file, resumeFrom)
+	if err != nil {
+		return err
+	}
+	if resp == nil {
+		// openDownload reported the file is already complete.
+		return nil
+	}
+	defer resp.Body.Close()
+
+	if !partial {
+		resumeFrom = 0
+	}
+	if o.maxBytes > 0 && int64(file.FileSize) > o.maxBytes {
+		return ErrFileTooLarge
+	}
+
+	flags := os.O_CREATE | os.O_WRONLY
+	if partial {
+		flags |= os.O_APPEND
+	} else {
+		flags |= os.O_TRUNC
+	}
+
+	out, err := os.OpenFile(path, flags, 0644)
+	if err != nil {
+		return wrapError(err)
+	}
+	defer out.Close()
+
+	body := io.Reader(resp.Body)
+	if o.maxBytes > 0 {
+		body = io.LimitReader(resp.Body, o.maxBytes-resumeFrom+1)
+	}
+
+	n, err := io.Copy(out, body)
+	if err != nil {
+		return wrapError(err)
+	}
+	if o.maxBytes > 0 && resumeFrom+n > o.maxBytes {
+		return ErrFileTooLarge
+	}
+	return nil
+}
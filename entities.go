@@ -0,0 +1,258 @@
+package main
+
+import (
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf16"
+)
+
+// EntityText returns the substring of m.Text that e spans. MessageEntity
+// offsets and lengths are documented as UTF-16 code units, so naively
+// slicing m.Text (a UTF-8 string) by them is wrong for any text containing
+// emoji or other non-BMP characters; EntityText converts correctly.
+func (m *Message) EntityText(e MessageEntity) string {
+	return utf16Substr(m.Text, e.Offset, e.Length)
+}
+
+// CaptionEntityText is EntityText for m.CaptionEntities.
+func (m *Message) CaptionEntityText(e MessageEntity) string {
+	return utf16Substr(m.Caption, e.Offset, e.Length)
+}
+
+func utf16Substr(s string, offset, length int) string {
+	units := utf16.Encode([]rune(s))
+	if offset < 0 || offset > len(units) {
+		return ""
+	}
+	end := offset + length
+	if end < offset || end > len(units) {
+		end = len(units)
+	}
+	return string(utf16.Decode(units[offset:end]))
+}
+
+var markupPattern = regexp.MustCompile(`\*\*(.+?)\*\*|_(.+?)_|` + "`(.+?)`" + `|\[(.+?)\]\((.+?)\)`)
+
+// NewEntities parses a small bold/italic/code/text_link markup —
+// **bold**, _italic_, `code` and [text](url) — out of text, returning the
+// plain text with the markers stripped plus the MessageEntity slice
+// describing it, with offsets expressed in UTF-16 code units as the Bot
+// API requires.
+func NewEntities(text string) (cleanText string, entities []MessageEntity) {
+	matches := markupPattern.FindAllStringSubmatchIndex(text, -1)
+
+	var clean strings.Builder
+	utf16Offset, last := 0, 0
+
+	appendPlain := func(s string) {
+		clean.WriteString(s)
+		utf16Offset += len(utf16.Encode([]rune(s)))
+	}
+
+	for _, g := range matches {
+		appendPlain(text[last:g[0]])
+		last = g[1]
+
+		var entityType EntityType
+		var content, url string
+		switch {
+		case g[2] >= 0:
+			entityType, content = EntityBold, text[g[2]:g[3]]
+		case g[4] >= 0:
+			entityType, content = EntityItalic, text[g[4]:g[5]]
+		case g[6] >= 0:
+			entityType, content = EntityCode, text[g[6]:g[7]]
+		case g[8] >= 0:
+			entityType, content, url = EntityTextLink, text[g[8]:g[9]], text[g[10]:g[11]]
+		}
+
+		entities = append(entities, MessageEntity{
+			Type:   entityType,
+			Offset: utf16Offset,
+			Length: len(utf16.Encode([]rune(content))),
+			URL:    url,
+		})
+		appendPlain(content)
+	}
+	appendPlain(text[last:])
+
+	return clean.String(), entities
+}
+
+// HTML reconstructs an HTML parse-mode string from m.Text and m.Entities.
+func (m *Message) HTML() string {
+	return renderEntities(m.Text, m.Entities, true)
+}
+
+// Markdown reconstructs a MarkdownV2 parse-mode string from m.Text and
+// m.Entities, escaping special characters in plain-text runs per
+// https://core.telegram.org/bots/api#markdownv2-style.
+func (m *Message) Markdown() string {
+	return renderEntities(m.Text, m.Entities, false)
+}
+
+// renderEntities reconstructs a parse-mode string for text, nesting
+// entities whose span is fully contained within another's (e.g. a
+// text_link inside a bold run). Entities of type EntityCode/EntityCodeBlock are
+// rendered as leaves using their own raw span text: the Bot API doesn't
+// support formatting nested inside code, so any entities contained in one
+// are dropped, matching Telegram's own semantics. Entities that only
+// partially overlap another (crossing spans, which the Bot API doesn't
+// produce) are dropped rather than rendered incorrectly.
+func renderEntities(text string, entities []MessageEntity, asHTML bool) string {
+	units := utf16.Encode([]rune(text))
+
+	sorted := make([]MessageEntity, len(entities))
+	copy(sorted, entities)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Offset != sorted[j].Offset {
+			return sorted[i].Offset < sorted[j].Offset
+		}
+		return sorted[i].Length > sorted[j].Length
+	})
+
+	return renderRange(units, sorted, 0, len(units), asHTML)
+}
+
+// renderRange renders units[start:end], wrapping any entities from sorted
+// that start within [start, end) and recursing into entities nested
+// inside them.
+func renderRange(units []uint16, sorted []MessageEntity, start, end int, asHTML bool) string {
+	var out strings.Builder
+	pos, i := start, 0
+	for i < len(sorted) {
+		e := sorted[i]
+		if e.Offset >= end {
+			break
+		}
+		if e.Offset < pos {
+			// Crosses the end of a preceding sibling rather than nesting
+			// inside or following it; can't be represented without
+			// rendering the overlap incorrectly, so it's dropped.
+			i++
+			continue
+		}
+		eEnd := e.Offset + e.Length
+		if eEnd > end {
+			if end < len(units) {
+				// Crosses the end of the enclosing entity; dropped for
+				// the same reason.
+				i++
+				continue
+			}
+			eEnd = end
+		}
+
+		writeEscaped(&out, units[pos:e.Offset], asHTML)
+		out.WriteString(renderEntity(units, e, sorted[i+1:], eEnd, asHTML))
+		pos = eEnd
+		i++
+		for i < len(sorted) && sorted[i].Offset < eEnd {
+			i++
+		}
+	}
+	writeEscaped(&out, units[pos:end], asHTML)
+	return out.String()
+}
+
+// renderEntity renders a single entity's content: nested recursively for
+// most types, but as a raw (non-recursing) leaf for EntityCode/EntityCodeBlock,
+// since the Bot API doesn't support entities nested inside code.
+func renderEntity(units []uint16, e MessageEntity, rest []MessageEntity, end int, asHTML bool) string {
+	if e.Type == EntityCode || e.Type == EntityCodeBlock {
+		return wrapEntity(e, string(utf16.Decode(units[e.Offset:end])), asHTML)
+	}
+	inner := renderRange(units, rest, e.Offset, end, asHTML)
+	return wrapEntity(e, inner, asHTML)
+}
+
+func writeEscaped(out *strings.Builder, units []uint16, asHTML bool) {
+	s := string(utf16.Decode(units))
+	if asHTML {
+		s = html.EscapeString(s)
+	} else {
+		s = escapeMarkdownV2(s)
+	}
+	out.WriteString(s)
+}
+
+// markdownV2Specials are the characters MarkdownV2 requires to be
+// backslash-escaped in plain-text runs (outside of entities). See
+// https://core.telegram.org/bots/api#markdownv2-style.
+const markdownV2Specials = "_*[]()~`>#+-=|{}.!\\"
+
+func escapeMarkdownV2(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(markdownV2Specials, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// escapeMarkdownV2Code escapes the narrower set of characters MarkdownV2
+// requires inside a code or pre entity: only backtick and backslash.
+func escapeMarkdownV2Code(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r == '`' || r == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// escapeMarkdownV2LinkURL escapes the characters MarkdownV2 requires
+// inside an inline link's URL: only closing parenthesis and backslash.
+func escapeMarkdownV2LinkURL(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r == ')' || r == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// wrapEntity wraps e's already-rendered content in e's markup. content is
+// the raw span text for leaf types (EntityCode/EntityCodeBlock) and gets
+// escaped here; for types that nest (e.g. EntityBold, EntityTextLink) it
+// is the output of renderRange, already escaped/wrapped by its own
+// recursive calls, so it is used as-is.
+func wrapEntity(e MessageEntity, content string, asHTML bool) string {
+	switch e.Type {
+	case EntityBold:
+		if asHTML {
+			return "<b>" + content + "</b>"
+		}
+		return "*" + content + "*"
+	case EntityItalic:
+		if asHTML {
+			return "<i>" + content + "</i>"
+		}
+		return "_" + content + "_"
+	case EntityCode:
+		if asHTML {
+			return "<code>" + html.EscapeString(content) + "</code>"
+		}
+		return "`" + escapeMarkdownV2Code(content) + "`"
+	case EntityCodeBlock:
+		if asHTML {
+			return "<pre>" + html.EscapeString(content) + "</pre>"
+		}
+		return "```\n" + escapeMarkdownV2Code(content) + "\n```"
+	case EntityTextLink:
+		if asHTML {
+			return `<a href="` + html.EscapeString(e.URL) + `">` + content + "</a>"
+		}
+		return "[" + content + "](" + escapeMarkdownV2LinkURL(e.URL) + ")"
+	default:
+		return content
+	}
+}
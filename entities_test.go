@@ -0,0 +1,111 @@
+package main
+
+import "testing"
+
+func TestEntityTextWithSurrogatePairEmoji(t *testing.T) {
+	// 👍 (U+1F44D) is a non-BMP rune and encodes as two UTF-16 code units,
+	// so naive byte slicing of m.Text would misalign with the entity's
+	// UTF-16 offset.
+	m := &Message{Text: "👍 bold"}
+	entity := MessageEntity{Type: EntityBold, Offset: 3, Length: 4}
+
+	if got := m.EntityText(entity); got != "bold" {
+		t.Fatalf("EntityText() = %q, want %q", got, "bold")
+	}
+}
+
+func TestCaptionEntityText(t *testing.T) {
+	m := &Message{Caption: "see 😀 code"}
+	entity := MessageEntity{Type: EntityCode, Offset: 7, Length: 4}
+
+	if got := m.CaptionEntityText(entity); got != "code" {
+		t.Fatalf("CaptionEntityText() = %q, want %q", got, "code")
+	}
+}
+
+func TestNewEntitiesRoundTrip(t *testing.T) {
+	clean, entities := NewEntities("👍 **bold** and `code` and [link](https://example.com)")
+
+	want := "👍 bold and code and link"
+	if clean != want {
+		t.Fatalf("clean text = %q, want %q", clean, want)
+	}
+
+	m := &Message{Text: clean}
+	var got []string
+	for _, e := range entities {
+		got = append(got, string(e.Type)+":"+m.EntityText(e))
+	}
+
+	wantEntities := []string{"bold:bold", "code:code", "text_link:link"}
+	if len(got) != len(wantEntities) {
+		t.Fatalf("got %d entities %v, want %v", len(got), got, wantEntities)
+	}
+	for i := range got {
+		if got[i] != wantEntities[i] {
+			t.Errorf("entity %d = %q, want %q", i, got[i], wantEntities[i])
+		}
+	}
+
+	if entities[2].URL != "https://example.com" {
+		t.Errorf("link URL = %q, want https://example.com", entities[2].URL)
+	}
+}
+
+func TestMessageHTMLAndMarkdown(t *testing.T) {
+	m := &Message{
+		Text: "👍 bold and code",
+		Entities: []MessageEntity{
+			{Type: EntityBold, Offset: 3, Length: 4},
+			{Type: EntityCode, Offset: 12, Length: 4},
+		},
+	}
+
+	if got, want := m.HTML(), "👍 <b>bold</b> and <code>code</code>"; got != want {
+		t.Errorf("HTML() = %q, want %q", got, want)
+	}
+	if got, want := m.Markdown(), "👍 *bold* and `code`"; got != want {
+		t.Errorf("Markdown() = %q, want %q", got, want)
+	}
+}
+
+func TestMessageHTMLAndMarkdownNestedEntities(t *testing.T) {
+	// A text_link nested inside a bold run, e.g. Telegram's own
+	// "**[link](url)**" bold-link combination.
+	m := &Message{
+		Text: "see bold link here",
+		Entities: []MessageEntity{
+			{Type: EntityBold, Offset: 4, Length: 9},
+			{Type: EntityTextLink, Offset: 9, Length: 4, URL: "https://example.com"},
+		},
+	}
+
+	if got, want := m.HTML(), `see <b>bold <a href="https://example.com">link</a></b> here`; got != want {
+		t.Errorf("HTML() = %q, want %q", got, want)
+	}
+	if got, want := m.Markdown(), `see *bold [link](https://example.com)* here`; got != want {
+		t.Errorf("Markdown() = %q, want %q", got, want)
+	}
+}
+
+func TestMessageHTMLAndMarkdownCodeBlock(t *testing.T) {
+	m := &Message{
+		Text:     "see x<y code",
+		Entities: []MessageEntity{{Type: EntityCodeBlock, Offset: 4, Length: 8}},
+	}
+
+	if got, want := m.HTML(), "see <pre>x&lt;y code</pre>"; got != want {
+		t.Errorf("HTML() = %q, want %q", got, want)
+	}
+	if got, want := m.Markdown(), "see ```\nx<y code\n```"; got != want {
+		t.Errorf("Markdown() = %q, want %q", got, want)
+	}
+}
+
+func TestMarkdownEscapesSpecialCharacters(t *testing.T) {
+	m := &Message{Text: "2+2=4 (really!)"}
+
+	if got, want := m.Markdown(), `2\+2\=4 \(really\!\)`; got != want {
+		t.Errorf("Markdown() = %q, want %q", got, want)
+	}
+}
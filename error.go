@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ResponseParameters carries extra information about an unsuccessful
+// request, as returned by the Bot API in the "parameters" field.
+type ResponseParameters struct {
+	// RetryAfter is set when the bot is flood-controlled and must wait
+	// this many seconds before sending further requests.
+	RetryAfter int `json:"retry_after,omitempty"`
+
+	// MigrateToChatID is set when a group has been migrated to a
+	// supergroup with this chat ID.
+	MigrateToChatID int64 `json:"migrate_to_chat_id,omitempty"`
+}
+
+// APIError represents an error returned by the Bot API, preserving the
+// numeric error code and description, plus any ResponseParameters.
+type APIError struct {
+	Code        int
+	Description string
+
+	// RetryAfter is non-zero when the API signalled flood control.
+	RetryAfter int
+
+	// MigrateToChatID is non-zero when the chat has migrated to a
+	// supergroup.
+	MigrateToChatID int64
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("telebot: %s (%d)", e.Description, e.Code)
+}
+
+// IsTooManyRequests reports whether err is an APIError signalling flood
+// control, returning how long to wait before retrying.
+func IsTooManyRequests(err error) (retryAfter time.Duration, ok bool) {
+	apiErr, isAPIErr := err.(*APIError)
+	if !isAPIErr || apiErr.RetryAfter == 0 {
+		return 0, false
+	}
+	return time.Duration(apiErr.RetryAfter) * time.Second, true
+}
+
+// IsChatMigrated reports whether err is an APIError signalling that the
+// chat has migrated to a supergroup, returning the new chat ID.
+func IsChatMigrated(err error) (newID int64, ok bool) {
+	apiErr, isAPIErr := err.(*APIError)
+	if !isAPIErr || apiErr.MigrateToChatID == 0 {
+		return 0, false
+	}
+	return apiErr.MigrateToChatID, true
+}
+
+// apiResponse is the generic envelope every Bot API method responds with.
+type apiResponse struct {
+	OK          bool                `json:"ok"`
+	Result      json.RawMessage     `json:"result"`
+	ErrorCode   int                 `json:"error_code"`
+	Description string              `json:"description"`
+	Parameters  *ResponseParameters `json:"parameters"`
+}
+
+// asError turns a non-OK apiResponse into an *APIError.
+func (r *apiResponse) asError() *APIError {
+	apiErr := &APIError{Code: r.ErrorCode, Description: r.Description}
+	if r.Parameters != nil {
+		apiErr.RetryAfter = r.Parameters.RetryAfter
+		apiErr.MigrateToChatID = r.Parameters.MigrateToChatID
+	}
+	return apiErr
+}
+
+// defaultFloodWaitCap bounds how long raw will keep sleeping and retrying
+// on repeated flood-control responses when Settings.HandleFloodWait is set.
+const defaultFloodWaitCap = 5 * time.Minute
+
+// raw calls the given Bot API method with params JSON-encoded as the
+// request body and decodes the result into v, if v is non-nil.
+//
+// When b.HandleFloodWait is set, a TooManyRequests response is handled
+// transparently: raw sleeps for RetryAfter and retries, up to
+// b.FloodWaitCap (or defaultFloodWaitCap if unset) of total waiting.
+func (b *Bot) raw(method string, params map[string]interface{}, v interface{}) error {
+	floodCap := b.FloodWaitCap
+	if floodCap == 0 {
+		floodCap = defaultFloodWaitCap
+	}
+
+	var waited time.Duration
+	for {
+		r, err := b.doRaw(method, params)
+		if err != nil {
+			return err
+		}
+		if r.OK {
+			if v != nil {
+				return json.Unmarshal(r.Result, v)
+			}
+			return nil
+		}
+
+		apiErr := r.asError()
+
+		if newID, ok := IsChatMigrated(apiErr); ok && params != nil {
+			if chatID, ok := params["chat_id"]; ok && chatID != newID {
+				params["chat_id"] = newID
+				continue
+			}
+		}
+
+		if retryAfter, ok := IsTooManyRequests(apiErr); ok && b.HandleFloodWait {
+			if waited+retryAfter > floodCap {
+				return apiErr
+			}
+			time.Sleep(retryAfter)
+			waited += retryAfter
+			continue
+		}
+
+		return apiErr
+	}
+}
+
+// doRaw performs a single Bot API call and returns the decoded envelope
+// without interpreting success or failure.
+func (b *Bot) doRaw(method string, params map[string]interface{}) (*apiResponse, error) {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	url := b.URL + "/bot" + b.Token + "/" + method
+	resp, err := b.client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer resp.Body.Close()
+
+	var r apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return nil, wrapError(err)
+	}
+	return &r, nil
+}
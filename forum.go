@@ -0,0 +1,117 @@
+package main
+
+// ForumTopic represents a topic in a forum supergroup.
+type ForumTopic struct {
+	ThreadID          int    `json:"message_thread_id"`
+	Name              string `json:"name"`
+	IconColor         int    `json:"icon_color,omitempty"`
+	IconCustomEmojiID string `json:"icon_custom_emoji_id,omitempty"`
+}
+
+// ForumTopicCreated is sent as a service message when a new forum topic is
+// created.
+type ForumTopicCreated struct {
+	Name              string `json:"name"`
+	IconColor         int    `json:"icon_color"`
+	IconCustomEmojiID string `json:"icon_custom_emoji_id,omitempty"`
+}
+
+// ForumTopicClosed is sent as a service message when a forum topic is
+// closed. It currently holds no fields.
+type ForumTopicClosed struct{}
+
+// ForumTopicReopened is sent as a service message when a forum topic is
+// reopened. It currently holds no fields.
+type ForumTopicReopened struct{}
+
+// ForumTopicEdited is sent as a service message when a forum topic is
+// edited.
+type ForumTopicEdited struct {
+	// (Optional) Name is set if the topic name was edited.
+	Name string `json:"name,omitempty"`
+
+	// (Optional) IconCustomEmojiID is set if the topic icon was edited.
+	IconCustomEmojiID string `json:"icon_custom_emoji_id,omitempty"`
+}
+
+// CreateForumTopic creates a topic in a forum supergroup chat, returning
+// the newly created ForumTopic. The bot must be an administrator with the
+// can_manage_topics right.
+func (b *Bot) CreateForumTopic(chat *Chat, name string, iconColor int, iconCustomEmojiID string) (*ForumTopic, error) {
+	params := map[string]interface{}{
+		"chat_id": chat.ID,
+		"name":    name,
+	}
+	if iconColor != 0 {
+		params["icon_color"] = iconColor
+	}
+	if iconCustomEmojiID != "" {
+		params["icon_custom_emoji_id"] = iconCustomEmojiID
+	}
+
+	topic := &ForumTopic{}
+	if err := b.raw("createForumTopic", params, topic); err != nil {
+		return nil, err
+	}
+	return topic, nil
+}
+
+// EditForumTopic edits the name and icon of a topic in a forum supergroup
+// chat. The bot must be an administrator with the can_manage_topics right,
+// unless it is the creator of the topic.
+func (b *Bot) EditForumTopic(chat *Chat, threadID int, name string, iconCustomEmojiID string) error {
+	params := map[string]interface{}{
+		"chat_id":           chat.ID,
+		"message_thread_id": threadID,
+	}
+	if name != "" {
+		params["name"] = name
+	}
+	if iconCustomEmojiID != "" {
+		params["icon_custom_emoji_id"] = iconCustomEmojiID
+	}
+	return b.raw("editForumTopic", params, nil)
+}
+
+// CloseForumTopic closes an open topic in a forum supergroup chat.
+func (b *Bot) CloseForumTopic(chat *Chat, threadID int) error {
+	return b.raw("closeForumTopic", map[string]interface{}{
+		"chat_id":           chat.ID,
+		"message_thread_id": threadID,
+	}, nil)
+}
+
+// ReopenForumTopic reopens a closed topic in a forum supergroup chat.
+func (b *Bot) ReopenForumTopic(chat *Chat, threadID int) error {
+	return b.raw("reopenForumTopic", map[string]interface{}{
+		"chat_id":           chat.ID,
+		"message_thread_id": threadID,
+	}, nil)
+}
+
+// DeleteForumTopic deletes a forum topic along with all its messages.
+func (b *Bot) DeleteForumTopic(chat *Chat, threadID int) error {
+	return b.raw("deleteForumTopic", map[string]interface{}{
+		"chat_id":           chat.ID,
+		"message_thread_id": threadID,
+	}, nil)
+}
+
+// UnpinAllForumTopicMessages clears the list of pinned messages in a forum
+// topic.
+func (b *Bot) UnpinAllForumTopicMessages(chat *Chat, threadID int) error {
+	return b.raw("unpinAllForumTopicMessages", map[string]interface{}{
+		"chat_id":           chat.ID,
+		"message_thread_id": threadID,
+	}, nil)
+}
+
+// GetForumTopicIconStickers returns the custom emoji stickers that can be
+// used as a forum topic icon by any user.
+func (b *Bot) GetForumTopicIconStickers() ([]Sticker, error) {
+	var stickers []Sticker
+	if err := b.raw("getForumTopicIconStickers", nil, &stickers); err != nil {
+		return nil, err
+	}
+	return stickers, nil
+}
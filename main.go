@@ -1,9 +1,12 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"io"
 	"log"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/pkg/errors"
@@ -11,7 +14,8 @@ import (
 )
 
 type User struct {
-	ID int `json:"id"`
+	// ID may exceed 2^31, so it is always int64, never int.
+	ID int64 `json:"id"`
 
 	FirstName    string `json:"first_name"`
 	LastName     string `json:"last_name"`
@@ -25,6 +29,15 @@ type User struct {
 	SupportsInline  bool `json:"supports_inline_queries"`
 }
 
+// Recipient returns a string representation of the user's ID, letting
+// User satisfy the Recipient interface. Kept as a string (rather than
+// exposing ID directly) so callers that build requests with
+// strconv.Itoa(user.ID) from before the int64 migration only need to
+// switch to user.Recipient().
+func (u *User) Recipient() string {
+	return strconv.FormatInt(u.ID, 10)
+}
+
 type MessageEntity struct {
 	// Specifies entity type.
 	Type EntityType `json:"type"`
@@ -279,6 +292,22 @@ type Message struct {
 
 	// For a service message, represents about a change in auto-delete timer settings.
 	AutoDeleteTimer *MessageAutoDeleteTimerChanged `json:"message_auto_delete_timer_changed,omitempty"`
+
+	// ThreadID is the unique identifier of the forum topic this message
+	// belongs to, for messages in forum supergroups.
+	ThreadID int `json:"message_thread_id,omitempty"`
+
+	// For a service message, represents a forum topic created in the chat.
+	ForumTopicCreated *ForumTopicCreated `json:"forum_topic_created,omitempty"`
+
+	// For a service message, represents a forum topic closed in the chat.
+	ForumTopicClosed *ForumTopicClosed `json:"forum_topic_closed,omitempty"`
+
+	// For a service message, represents a forum topic reopened in the chat.
+	ForumTopicReopened *ForumTopicReopened `json:"forum_topic_reopened,omitempty"`
+
+	// For a service message, represents a forum topic edited in the chat.
+	ForumTopicEdited *ForumTopicEdited `json:"forum_topic_edited,omitempty"`
 }
 
 type Callback struct {
@@ -444,6 +473,9 @@ type Chat struct {
 	CanSetStickerSet bool          `json:"can_set_sticker_set,omitempty"`
 	LinkedChatID     int64         `json:"linked_chat_id,omitempty"`
 	ChatLocation     *ChatLocation `json:"location,omitempty"`
+
+	// IsForum is true if the supergroup has topics (forum) mode enabled.
+	IsForum bool `json:"is_forum,omitempty"`
 }
 
 type ChatMemberUpdated struct {
@@ -559,6 +591,16 @@ type Bot struct {
 	URL     string
 	Updates chan Update
 	Poller  Poller
+
+	// HandleFloodWait, if true, makes raw Bot API calls sleep for the
+	// RetryAfter duration reported by a TooManyRequests APIError and
+	// retry automatically, instead of returning the error to the caller.
+	HandleFloodWait bool
+
+	// FloodWaitCap bounds the total time raw Bot API calls will spend
+	// sleeping on repeated flood-control responses when HandleFloodWait
+	// is set. Defaults to defaultFloodWaitCap when zero.
+	FloodWaitCap time.Duration
 	// contains filtered or unexported fields
 }
 
@@ -581,7 +623,14 @@ type File struct {
 	// contains filtered or unexported fields
 }
 
+// GetFile is GetFileWithContext with context.Background().
 func (b *Bot) GetFile(file *File) (io.ReadCloser, error) {
+	return b.GetFileWithContext(context.Background(), file)
+}
+
+// GetFileWithContext is GetFile, but the request can be cancelled or timed
+// out via ctx independently of the Bot's global HTTP client timeout.
+func (b *Bot) GetFileWithContext(ctx context.Context, file *File) (io.ReadCloser, error) {
 	f, err := b.FileByID(file.FileID)
 	if err != nil {
 		return nil, err
@@ -590,7 +639,7 @@ func (b *Bot) GetFile(file *File) (io.ReadCloser, error) {
 	url := b.URL + "/file/bot" + b.Token + "/" + f.FilePath
 	file.FilePath = f.FilePath // saving file path
 
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, wrapError(err)
 	}
@@ -601,8 +650,13 @@ func (b *Bot) GetFile(file *File) (io.ReadCloser, error) {
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		resp.Body.Close()
-		return nil, errors.Errorf("telebot: expected status 200 but got %s", resp.Status)
+		defer resp.Body.Close()
+
+		var r apiResponse
+		if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+			return nil, errors.Errorf("telebot: expected status 200 but got %s", resp.Status)
+		}
+		return nil, r.asError()
 	}
 
 	return resp.Body, nil
@@ -0,0 +1,62 @@
+package main
+
+// SendOptions represents a set of options that are applied when sending,
+// replying to, or editing a message through the Send/Reply/Edit family of
+// calls.
+//
+// NOTE: this package does not define Send/Reply/Edit, so nothing here
+// calls extractOptions or embedThreadID. ThreadID takes effect only where
+// a caller passes the *SendOptions through embedThreadID.
+type SendOptions struct {
+	// ReplyTo references the message the current message is a reply to.
+	ReplyTo *Message
+
+	// ReplyMarkup holds a keyboard or inline buttons to attach.
+	ReplyMarkup *InlineKeyboardMarkup
+
+	// DisableNotification silently sends the message when true.
+	DisableNotification bool
+
+	// ParseMode controls how the message's Text is parsed.
+	ParseMode ParseMode
+
+	// ThreadID targets a specific forum topic thread of the chat. Zero
+	// means the chat's General topic (or any non-forum chat).
+	ThreadID int
+}
+
+// ToThread is a send option that targets a specific forum topic thread;
+// extractOptions turns it into SendOptions.ThreadID:
+//
+//	opts := extractOptions([]interface{}{ToThread(42)}) // opts.ThreadID == 42
+type ToThread int
+
+// extractOptions merges a slice of heterogeneous option values passed to
+// Send/Reply/Edit into a single SendOptions, applying ToThread alongside
+// the other recognized option types.
+func extractOptions(how []interface{}) *SendOptions {
+	opts := &SendOptions{}
+
+	for _, item := range how {
+		switch opt := item.(type) {
+		case *SendOptions:
+			opts = opt
+		case *InlineKeyboardMarkup:
+			opts.ReplyMarkup = opt
+		case ParseMode:
+			opts.ParseMode = opt
+		case ToThread:
+			opts.ThreadID = int(opt)
+		}
+	}
+
+	return opts
+}
+
+// embedThreadID adds the message_thread_id parameter to params when the
+// options request a specific forum topic thread.
+func (o *SendOptions) embedThreadID(params map[string]interface{}) {
+	if o != nil && o.ThreadID != 0 {
+		params["message_thread_id"] = o.ThreadID
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import "testing"
+
+func TestExtractOptionsMergesRecognizedTypes(t *testing.T) {
+	markup := &InlineKeyboardMarkup{}
+
+	const mode ParseMode = "HTML"
+	opts := extractOptions([]interface{}{markup, mode, ToThread(42)})
+
+	if opts.ReplyMarkup != markup {
+		t.Errorf("ReplyMarkup = %v, want %v", opts.ReplyMarkup, markup)
+	}
+	if opts.ParseMode != mode {
+		t.Errorf("ParseMode = %q, want %q", opts.ParseMode, mode)
+	}
+	if opts.ThreadID != 42 {
+		t.Errorf("ThreadID = %d, want 42", opts.ThreadID)
+	}
+}
+
+func TestExtractOptionsIgnoresUnrecognizedTypes(t *testing.T) {
+	opts := extractOptions([]interface{}{7, struct{}{}})
+
+	if opts.ReplyMarkup != nil || opts.ParseMode != "" || opts.ThreadID != 0 {
+		t.Errorf("extractOptions() = %+v, want zero value", opts)
+	}
+}
+
+func TestExtractOptionsExplicitSendOptionsWins(t *testing.T) {
+	explicit := &SendOptions{ThreadID: 7}
+
+	opts := extractOptions([]interface{}{ToThread(42), explicit})
+
+	if opts != explicit {
+		t.Errorf("extractOptions() = %v, want the explicit *SendOptions %v", opts, explicit)
+	}
+}
+
+func TestEmbedThreadID(t *testing.T) {
+	params := map[string]interface{}{}
+	(&SendOptions{ThreadID: 42}).embedThreadID(params)
+
+	if params["message_thread_id"] != 42 {
+		t.Errorf("message_thread_id = %v, want 42", params["message_thread_id"])
+	}
+}
+
+func TestEmbedThreadIDNilOrZeroIsNoop(t *testing.T) {
+	params := map[string]interface{}{}
+	(*SendOptions)(nil).embedThreadID(params)
+	(&SendOptions{}).embedThreadID(params)
+
+	if _, ok := params["message_thread_id"]; ok {
+		t.Errorf("message_thread_id should be absent, got %v", params)
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+)
+
+// largeUserID exceeds 2^31, which no longer fits in a (32-bit) int.
+//
+// Send and GetFile aren't round-tripped here: neither touches User.ID at
+// all (GetFile resolves a File by ID, and Send isn't part of this
+// package), so there is no int64 path through them to lock in beyond what
+// TestUpdateDecodeLargeUserID and TestUserRecipient already cover.
+const largeUserID int64 = 1<<31 + 12345
+
+func TestUpdateDecodeLargeUserID(t *testing.T) {
+	body := []byte(`{
+		"update_id": 1,
+		"message": {
+			"message_id": 1,
+			"date": 0,
+			"chat": {"id": 1, "type": "private"},
+			"from": {"id": ` + strconv.FormatInt(largeUserID, 10) + `, "is_bot": false}
+		}
+	}`)
+
+	var u Update
+	if err := json.Unmarshal(body, &u); err != nil {
+		t.Fatalf("unmarshal update: %v", err)
+	}
+	if u.Message == nil || u.Message.Sender == nil {
+		t.Fatal("message or sender not decoded")
+	}
+	if u.Message.Sender.ID != largeUserID {
+		t.Fatalf("got sender ID %d, want %d", u.Message.Sender.ID, largeUserID)
+	}
+}
+
+func TestUserRecipient(t *testing.T) {
+	u := &User{ID: largeUserID}
+	if got, want := u.Recipient(), strconv.FormatInt(largeUserID, 10); got != want {
+		t.Fatalf("Recipient() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path"
+	"time"
+)
+
+// WebhookInfo describes the current status of a webhook, as returned by
+// getWebhookInfo.
+type WebhookInfo struct {
+	URL                string   `json:"url"`
+	HasCustomCert      bool     `json:"has_custom_certificate"`
+	PendingUpdateCount int      `json:"pending_update_count"`
+	IPAddress          string   `json:"ip_address,omitempty"`
+	LastErrorDate      int64    `json:"last_error_date,omitempty"`
+	LastErrorMessage   string   `json:"last_error_message,omitempty"`
+	MaxConnections     int      `json:"max_connections,omitempty"`
+	AllowedUpdates     []string `json:"allowed_updates,omitempty"`
+}
+
+// GetWebhookInfo returns the current webhook status, useful for checking
+// PendingUpdateCount and LastErrorMessage without switching to a webhook.
+func (b *Bot) GetWebhookInfo() (*WebhookInfo, error) {
+	info := &WebhookInfo{}
+	if err := b.raw("getWebhookInfo", nil, info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// Webhook implements Poller by registering a webhook URL with the Bot API
+// and running a local HTTP server (or attaching to a caller-provided
+// ServeMux) that receives the updates Telegram pushes to it.
+type Webhook struct {
+	// Listen is the local address to listen on, e.g. ":8443". Ignored
+	// when Mux is set, since the caller then owns serving Endpoint.
+	Listen string
+
+	// Endpoint is the path Telegram will POST updates to, e.g. "/bot".
+	Endpoint string
+
+	// PublicURL is the externally reachable HTTPS URL Telegram should
+	// push updates to, including Endpoint.
+	PublicURL string
+
+	// SecretToken, if set, is verified against the
+	// X-Telegram-Bot-Api-Secret-Token header on every incoming request.
+	SecretToken string
+
+	// CertFile, if set, is uploaded to Telegram as the webhook's
+	// self-signed certificate and used to serve TLS locally together
+	// with KeyFile.
+	CertFile string
+	KeyFile  string
+
+	// IP overrides the IP address used to connect to the webhook.
+	IP string
+
+	// MaxConnections caps the number of simultaneous HTTPS connections
+	// used for update delivery, 1-100 (Telegram defaults to 40).
+	MaxConnections int
+
+	// AllowedUpdates lists update types to receive, same semantics as
+	// LongPoller.
+	AllowedUpdates []string
+
+	// DropPendingUpdates discards any updates queued while the webhook
+	// was unset.
+	DropPendingUpdates bool
+
+	// Mux, if set, is used to register Endpoint instead of starting an
+	// embedded http.Server; the caller owns listening and serving it.
+	Mux *http.ServeMux
+
+	server *http.Server
+}
+
+// Poll implements Poller. It registers the webhook with setWebhook, serves
+// incoming updates until it receives on stop, closes stop, then
+// unregisters with deleteWebhook.
+func (w *Webhook) Poll(b *Bot, updates chan Update, stop chan struct{}) {
+	if err := b.setWebhook(w); err != nil {
+		log.Println("telebot: setWebhook:", err)
+		close(stop)
+		return
+	}
+	defer b.deleteWebhook()
+
+	mux := w.Mux
+	if mux == nil {
+		mux = http.NewServeMux()
+	}
+	mux.HandleFunc(w.Endpoint, w.handle(updates))
+
+	if w.Mux != nil {
+		<-stop
+		close(stop)
+		return
+	}
+
+	w.server = &http.Server{Addr: w.Listen, Handler: mux}
+	go func() {
+		var err error
+		if w.CertFile != "" {
+			err = w.server.ListenAndServeTLS(w.CertFile, w.KeyFile)
+		} else {
+			err = w.server.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
+			log.Println("telebot: webhook server:", err)
+		}
+	}()
+
+	<-stop
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	w.server.Shutdown(ctx)
+	close(stop)
+}
+
+// handle returns the http.HandlerFunc that verifies the secret token,
+// decodes the Update body, and forwards it into updates.
+func (w *Webhook) handle(updates chan Update) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		if w.SecretToken != "" && req.Header.Get("X-Telegram-Bot-Api-Secret-Token") != w.SecretToken {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var u Update
+		if err := json.NewDecoder(req.Body).Decode(&u); err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		updates <- u
+		rw.WriteHeader(http.StatusOK)
+	}
+}
+
+// setWebhook registers w with Telegram via setWebhook, uploading w.CertFile
+// as the webhook's self-signed certificate when set.
+func (b *Bot) setWebhook(w *Webhook) error {
+	params := map[string]interface{}{
+		"url": w.PublicURL,
+	}
+	if w.SecretToken != "" {
+		params["secret_token"] = w.SecretToken
+	}
+	if w.IP != "" {
+		params["ip_address"] = w.IP
+	}
+	if w.MaxConnections != 0 {
+		params["max_connections"] = w.MaxConnections
+	}
+	if w.DropPendingUpdates {
+		params["drop_pending_updates"] = true
+	}
+	if len(w.AllowedUpdates) != 0 {
+		params["allowed_updates"] = w.AllowedUpdates
+	}
+
+	if w.CertFile == "" {
+		return b.raw("setWebhook", params, nil)
+	}
+
+	fields, err := paramsToFormFields(params)
+	if err != nil {
+		return err
+	}
+	return b.rawMultipart("setWebhook", fields, "certificate", w.CertFile)
+}
+
+// paramsToFormFields renders JSON-shaped params as the string fields a
+// multipart/form-data request expects: strings pass through, and any other
+// value is JSON-encoded (booleans and numbers becoming their literal form,
+// slices becoming a JSON array string).
+func paramsToFormFields(params map[string]interface{}) (map[string]string, error) {
+	fields := make(map[string]string, len(params))
+	for k, v := range params {
+		if s, ok := v.(string); ok {
+			fields[k] = s
+			continue
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, wrapError(err)
+		}
+		fields[k] = string(b)
+	}
+	return fields, nil
+}
+
+// deleteWebhook unregisters the currently configured webhook.
+func (b *Bot) deleteWebhook() error {
+	return b.raw("deleteWebhook", nil, nil)
+}
+
+// rawMultipart performs a multipart/form-data Bot API call, attaching the
+// file at filePath under fieldName alongside the given string fields.
+func (b *Bot) rawMultipart(method string, fields map[string]string, fieldName, filePath string) error {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return wrapError(err)
+	}
+	defer f.Close()
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for k, v := range fields {
+		if err := mw.WriteField(k, v); err != nil {
+			return wrapError(err)
+		}
+	}
+	part, err := mw.CreateFormFile(fieldName, path.Base(filePath))
+	if err != nil {
+		return wrapError(err)
+	}
+	if _, err := io.Copy(part, f); err != nil {
+		return wrapError(err)
+	}
+	if err := mw.Close(); err != nil {
+		return wrapError(err)
+	}
+
+	url := b.URL + "/bot" + b.Token + "/" + method
+	resp, err := b.client.Post(url, mw.FormDataContentType(), &body)
+	if err != nil {
+		return wrapError(err)
+	}
+	defer resp.Body.Close()
+
+	var r apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return wrapError(err)
+	}
+	if !r.OK {
+		return r.asError()
+	}
+	return nil
+}